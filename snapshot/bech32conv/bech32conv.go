@@ -0,0 +1,47 @@
+// Package bech32conv converts bech32-encoded Cosmos SDK addresses from one
+// human-readable prefix (HRP) to another by decoding and re-encoding the
+// underlying data, so the result carries a valid checksum instead of the
+// broken one a plain string substitution would leave behind.
+package bech32conv
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+// ConvertPrefix decodes addr and re-encodes its data under prefix. The
+// original HRP is discarded; only the payload survives the round trip, so
+// the result always checksums correctly for prefix.
+func ConvertPrefix(addr, prefix string) (string, error) {
+	_, data, err := bech32.Decode(addr)
+	if err != nil {
+		return "", fmt.Errorf("decoding bech32 address %q: %w", addr, err)
+	}
+
+	converted, err := bech32.Encode(prefix, data)
+	if err != nil {
+		return "", fmt.Errorf("encoding address with prefix %q: %w", prefix, err)
+	}
+
+	return converted, nil
+}
+
+// DecodeDataHex decodes addr and returns its underlying address bytes,
+// converted from 5-bit groups back to 8-bit bytes, as a hex string. It's
+// used to surface the raw address bytes in a conversion manifest so
+// operators can spot two addresses that decode to the same bytes.
+func DecodeDataHex(addr string) (string, error) {
+	_, data, err := bech32.Decode(addr)
+	if err != nil {
+		return "", fmt.Errorf("decoding bech32 address %q: %w", addr, err)
+	}
+
+	converted, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", fmt.Errorf("converting bech32 data for %q: %w", addr, err)
+	}
+
+	return hex.EncodeToString(converted), nil
+}
@@ -0,0 +1,26 @@
+package bech32conv
+
+import "testing"
+
+func TestConvertPrefix(t *testing.T) {
+	const (
+		unicornAddr = "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh"
+		wantAddr    = "esim1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnn0w2fx"
+	)
+
+	got, err := ConvertPrefix(unicornAddr, "esim")
+	if err != nil {
+		t.Fatalf("ConvertPrefix(%q) returned error: %v", unicornAddr, err)
+	}
+	if got != wantAddr {
+		t.Errorf("ConvertPrefix(%q) = %q, want %q", unicornAddr, got, wantAddr)
+	}
+}
+
+func TestConvertPrefixInvalidChecksum(t *testing.T) {
+	const badAddr = "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrx"
+
+	if _, err := ConvertPrefix(badAddr, "esim"); err == nil {
+		t.Errorf("ConvertPrefix(%q) succeeded, want checksum error", badAddr)
+	}
+}
@@ -0,0 +1,106 @@
+// Package config loads the prefix-mapping configuration that drives the
+// genesis rewriter, so the same binary can be reused for chains other than
+// the original unicorn -> esim rename, and can rewrite every HRP family
+// consistently in one pass.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Mapping is a single old-to-new prefix pair.
+type Mapping struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// Config declares the prefix mappings for every Cosmos SDK HRP family a
+// genesis rewrite needs to touch, plus the address segment of token factory
+// denoms.
+type Config struct {
+	Account            Mapping `json:"account" yaml:"account"`
+	ValOper            Mapping `json:"valoper" yaml:"valoper"`
+	ValCons            Mapping `json:"valcons" yaml:"valcons"`
+	Pub                Mapping `json:"pub" yaml:"pub"`
+	FactoryDenomPrefix Mapping `json:"factory_denom_prefix" yaml:"factory_denom_prefix"`
+}
+
+// Default returns the Config equivalent of the tool's original hard-coded
+// unicorn -> esim rename.
+func Default() Config {
+	return DeriveFromAccountPrefix("unicorn", "esim")
+}
+
+// DeriveFromAccountPrefix builds a Config for chains that follow the
+// standard Cosmos SDK convention of deriving valoper/valcons/pub HRPs by
+// appending a suffix to the account prefix.
+func DeriveFromAccountPrefix(from, to string) Config {
+	return Config{
+		Account:            Mapping{From: from, To: to},
+		ValOper:            Mapping{From: from + "valoper", To: to + "valoper"},
+		ValCons:            Mapping{From: from + "valcons", To: to + "valcons"},
+		Pub:                Mapping{From: from + "pub", To: to + "pub"},
+		FactoryDenomPrefix: Mapping{From: from, To: to},
+	}
+}
+
+// Load reads a Config from a JSON or YAML file, selected by its extension.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parsing YAML config: %w", err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parsing JSON config: %w", err)
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported config extension %q (use .json, .yaml or .yml)", ext)
+	}
+
+	return cfg, nil
+}
+
+// Mappings returns every prefix mapping the config declares, for callers
+// that need to try each HRP family uniformly (e.g. the bech32 candidate
+// scanner). Mappings with an empty From are omitted. The result is sorted by
+// From length, longest first, so a caller that matches on the first prefix
+// an address has doesn't mistake a valoper/valcons/pub address for a plain
+// account address just because the account prefix happens to be a prefix of
+// the others (e.g. "unicorn" is a prefix of "unicornvaloper").
+func (c Config) Mappings() []Mapping {
+	all := []Mapping{c.Account, c.ValOper, c.ValCons, c.Pub, c.FactoryDenomPrefix}
+	out := make([]Mapping, 0, len(all))
+	for _, m := range all {
+		if m.From != "" {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return len(out[i].From) > len(out[j].From) })
+	return out
+}
+
+// ContainsAnyPrefix reports whether s contains any of the configured "from"
+// prefixes.
+func (c Config) ContainsAnyPrefix(s string) bool {
+	for _, m := range c.Mappings() {
+		if strings.Contains(s, m.From) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeriveFromAccountPrefix(t *testing.T) {
+	cfg := DeriveFromAccountPrefix("unicorn", "esim")
+
+	cases := []struct {
+		name string
+		got  Mapping
+		want Mapping
+	}{
+		{"account", cfg.Account, Mapping{From: "unicorn", To: "esim"}},
+		{"valoper", cfg.ValOper, Mapping{From: "unicornvaloper", To: "esimvaloper"}},
+		{"valcons", cfg.ValCons, Mapping{From: "unicornvalcons", To: "esimvalcons"}},
+		{"pub", cfg.Pub, Mapping{From: "unicornpub", To: "esimpub"}},
+		{"factory_denom_prefix", cfg.FactoryDenomPrefix, Mapping{From: "unicorn", To: "esim"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.want {
+				t.Errorf("%s = %+v, want %+v", c.name, c.got, c.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	want := DeriveFromAccountPrefix("unicorn", "esim")
+	if got := Default(); got != want {
+		t.Errorf("Default() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		content  string
+	}{
+		{
+			name:     "json",
+			filename: "config.json",
+			content:  `{"account":{"from":"cosmos","to":"osmo"},"valoper":{"from":"cosmosvaloper","to":"osmovaloper"}}`,
+		},
+		{
+			name:     "yaml",
+			filename: "config.yaml",
+			content:  "account:\n  from: cosmos\n  to: osmo\nvaloper:\n  from: cosmosvaloper\n  to: osmovaloper\n",
+		},
+		{
+			name:     "yml",
+			filename: "config.yml",
+			content:  "account:\n  from: cosmos\n  to: osmo\nvaloper:\n  from: cosmosvaloper\n  to: osmovaloper\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, c.filename)
+			if err := os.WriteFile(path, []byte(c.content), 0644); err != nil {
+				t.Fatalf("writing config file: %v", err)
+			}
+
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load(%q) returned error: %v", path, err)
+			}
+			want := Mapping{From: "cosmos", To: "osmo"}
+			if got.Account != want {
+				t.Errorf("Account = %+v, want %+v", got.Account, want)
+			}
+		})
+	}
+}
+
+func TestLoadUnsupportedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("account = \"cosmos\""), 0644); err != nil {
+		t.Fatalf("writing config file: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load(%q) succeeded, want unsupported extension error", path)
+	}
+}
+
+func TestMappingsOmitsEmptyFrom(t *testing.T) {
+	cfg := Config{Account: Mapping{From: "cosmos", To: "osmo"}}
+	got := cfg.Mappings()
+	if len(got) != 1 || got[0] != cfg.Account {
+		t.Errorf("Mappings() = %+v, want only the account mapping", got)
+	}
+}
+
+func TestMappingsLongestPrefixFirst(t *testing.T) {
+	cfg := DeriveFromAccountPrefix("unicorn", "esim")
+	got := cfg.Mappings()
+	for i := 1; i < len(got); i++ {
+		if len(got[i-1].From) < len(got[i].From) {
+			t.Fatalf("Mappings() = %+v, not sorted longest-From-first", got)
+		}
+	}
+	if got[0].From != "unicornvaloper" && got[0].From != "unicornvalcons" {
+		t.Errorf("Mappings()[0].From = %q, want the longest prefix (unicornvaloper or unicornvalcons), so a candidate scanner checks it before the shorter account prefix", got[0].From)
+	}
+}
+
+func TestContainsAnyPrefix(t *testing.T) {
+	cfg := DeriveFromAccountPrefix("unicorn", "esim")
+
+	cases := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"account prefix", "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh", true},
+		{"valoper prefix", "unicornvaloper1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh", true},
+		{"no prefix", "cosmos1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := cfg.ContainsAnyPrefix(c.s); got != c.want {
+				t.Errorf("ContainsAnyPrefix(%q) = %v, want %v", c.s, got, c.want)
+			}
+		})
+	}
+}
@@ -9,23 +9,124 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-)
 
-// Constants for address conversion
-const (
-	OldPrefix = "unicorn"
-	NewPrefix = "esim"
+	"github.com/faddat/horse/snapshot/bech32conv"
+	"github.com/faddat/horse/snapshot/config"
+	"github.com/faddat/horse/snapshot/manifest"
+	"github.com/faddat/horse/snapshot/modules"
 )
 
-// Simple representation of a genesis file
+// genesisKnownFields lists the top-level genesis keys GenesisDoc explicitly
+// models. Every other key (app_hash, and whatever CometBFT adds next) is
+// round-tripped untouched through GenesisDoc.Extra instead of being dropped.
+var genesisKnownFields = map[string]bool{
+	"app_state":        true,
+	"chain_id":         true,
+	"genesis_time":     true,
+	"consensus_params": true,
+	"initial_height":   true,
+	"validators":       true,
+}
+
+// GenesisDoc is a partial representation of a genesis file: just enough to
+// rewrite app_state and chain_id. Extra holds every field this struct
+// doesn't model, so a round trip through GenesisDoc can't silently drop
+// app_hash or other fields CometBFT adds.
 type GenesisDoc struct {
-	AppState json.RawMessage `json:"app_state"`
-	// Other fields in genesis doc
+	AppState        json.RawMessage `json:"app_state"`
 	ChainID         string          `json:"chain_id"`
 	GenesisTime     string          `json:"genesis_time"`
 	ConsensusParams json.RawMessage `json:"consensus_params"`
 	InitialHeight   string          `json:"initial_height"`
-	Validators      json.RawMessage `json:"validators"`
+	Validators      json.RawMessage            `json:"validators"`
+	Extra           map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the known genesis fields as usual and stashes every
+// other top-level key in Extra.
+func (g *GenesisDoc) UnmarshalJSON(data []byte) error {
+	type known struct {
+		AppState        json.RawMessage `json:"app_state"`
+		ChainID         string          `json:"chain_id"`
+		GenesisTime     string          `json:"genesis_time"`
+		ConsensusParams json.RawMessage `json:"consensus_params"`
+		InitialHeight   string          `json:"initial_height"`
+		Validators      json.RawMessage `json:"validators"`
+	}
+	var k known
+	if err := json.Unmarshal(data, &k); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	extra := make(map[string]json.RawMessage)
+	for key, raw := range all {
+		if !genesisKnownFields[key] {
+			extra[key] = raw
+		}
+	}
+
+	g.AppState = k.AppState
+	g.ChainID = k.ChainID
+	g.GenesisTime = k.GenesisTime
+	g.ConsensusParams = k.ConsensusParams
+	g.InitialHeight = k.InitialHeight
+	g.Validators = k.Validators
+	g.Extra = extra
+	return nil
+}
+
+// MarshalJSON re-assembles the known fields and Extra into a single flat
+// object, so a field we never touch (app_hash, say) survives the round
+// trip with its value unchanged (whitespace inside it is compacted).
+func (g GenesisDoc) MarshalJSON() ([]byte, error) {
+	out := make(map[string]json.RawMessage, len(g.Extra)+6)
+	for key, raw := range g.Extra {
+		out[key] = raw
+	}
+
+	marshalField := func(key string, v interface{}) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshalling %s: %w", key, err)
+		}
+		out[key] = raw
+		return nil
+	}
+	if g.AppState != nil {
+		out["app_state"] = g.AppState
+	}
+	if err := marshalField("chain_id", g.ChainID); err != nil {
+		return nil, err
+	}
+	if err := marshalField("genesis_time", g.GenesisTime); err != nil {
+		return nil, err
+	}
+	if g.ConsensusParams != nil {
+		out["consensus_params"] = g.ConsensusParams
+	}
+	if err := marshalField("initial_height", g.InitialHeight); err != nil {
+		return nil, err
+	}
+	if g.Validators != nil {
+		out["validators"] = g.Validators
+	}
+
+	return json.Marshal(out)
+}
+
+// RunOptions bundles the CLI flags that drive a single conversion run.
+type RunOptions struct {
+	Config          config.Config
+	DisabledModules string
+	Streaming       bool
+	DryRun          bool
+	ManifestPath    string
+	AllowMerges     bool
+	Verify          bool
 }
 
 func main() {
@@ -33,16 +134,59 @@ func main() {
 	inputGenesisPtr := flag.String("input", "", "Input genesis.json file path")
 	outputGenesisPtr := flag.String("output", "", "Output genesis.json file path")
 	csvDirPtr := flag.String("csv-dir", "", "Directory containing CSV files to process (optional)")
+	modulesPtr := flag.String("modules", "", "Comma-separated list of module transformers to disable (e.g. wasm,gov)")
+	configPtr := flag.String("config", "", "Path to a JSON or YAML prefix-mapping config file (defaults to the unicorn->esim mapping)")
+	streamingPtr := flag.Bool("streaming", true, "Stream the genesis transformation token-by-token to bound memory use on multi-GB files (disable for the in-memory module-aware path)")
+	dryRunPtr := flag.Bool("dry-run", false, "Report what would be converted without writing output (implies -streaming=false)")
+	manifestPtr := flag.String("manifest", "", "Path to write a CSV or JSON manifest of every rewritten address (selected by extension; implies -streaming=false)")
+	allowMergesPtr := flag.Bool("allow-merges", false, "Proceed even if two original addresses collide onto the same converted address")
+	verifyPtr := flag.Bool("verify", false, "Validate the input and output genesis as a CometBFT genesis doc; refuse to write output that doesn't validate (implies -streaming=false)")
 	flag.Parse()
 
 	// Check for required arguments
 	if *inputGenesisPtr == "" || *outputGenesisPtr == "" {
-		fmt.Println("Usage: go run main.go -input <genesis.json> -output <new_genesis.json> [-csv-dir <directory>]")
+		fmt.Println("Usage: go run main.go -input <genesis.json> -output <new_genesis.json> [-csv-dir <directory>] [-modules <disabled,list>] [-config <path>] [-streaming=false] [-dry-run] [-manifest <path>] [-allow-merges] [-verify]")
 		os.Exit(1)
 	}
 
-	// Process the genesis file
-	err := processGenesisFile(*inputGenesisPtr, *outputGenesisPtr)
+	cfg := config.Default()
+	if *configPtr != "" {
+		loadedCfg, err := config.Load(*configPtr)
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		cfg = loadedCfg
+	}
+
+	opts := RunOptions{
+		Config:          cfg,
+		DisabledModules: *modulesPtr,
+		Streaming:       *streamingPtr,
+		DryRun:          *dryRunPtr,
+		ManifestPath:    *manifestPtr,
+		AllowMerges:     *allowMergesPtr,
+		Verify:          *verifyPtr,
+	}
+
+	// A dry run or -manifest needs the manifest built by the
+	// module-aware/generic walk path, which the streaming path can't produce.
+	// Verification needs a complete in-memory GenesisDoc to validate, which
+	// the streaming path also can't produce since it never holds more than
+	// one token at a time.
+	if opts.DryRun || opts.Verify || opts.ManifestPath != "" {
+		opts.Streaming = false
+	}
+
+	// Process the genesis file. Streaming bounds memory on multi-GB files
+	// but skips the module-aware transformers and manifest recording; fall
+	// back to -streaming=false to use those.
+	var err error
+	if opts.Streaming {
+		err = streamGenesisFile(*inputGenesisPtr, *outputGenesisPtr, opts.Config)
+	} else {
+		err = processGenesisFile(*inputGenesisPtr, *outputGenesisPtr, opts)
+	}
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -50,7 +194,7 @@ func main() {
 
 	// Process CSV files if directory provided
 	if *csvDirPtr != "" {
-		err := processCsvDirectory(*csvDirPtr)
+		err := processCsvDirectory(*csvDirPtr, opts)
 		if err != nil {
 			fmt.Printf("Error processing CSV files: %v\n", err)
 			os.Exit(1)
@@ -58,8 +202,13 @@ func main() {
 	}
 }
 
-// processGenesisFile handles the conversion of a genesis file
-func processGenesisFile(inputFile, outputFile string) error {
+// processGenesisFile handles the conversion of a genesis file, driven by
+// opts. opts.DisabledModules skips the named module transformers (see the
+// modules package), letting operators opt individual modules out of the
+// module-aware rewrite. When opts.DryRun or opts.ManifestPath is set, every
+// rewritten address is recorded in a manifest rather than (or alongside)
+// writing the converted genesis.
+func processGenesisFile(inputFile, outputFile string, opts RunOptions) error {
 	fmt.Printf("Processing genesis file %s -> %s\n", inputFile, outputFile)
 
 	// Read the input genesis file
@@ -68,6 +217,12 @@ func processGenesisFile(inputFile, outputFile string) error {
 		return fmt.Errorf("error reading genesis file: %v", err)
 	}
 
+	if opts.Verify {
+		if err := verifyGenesisBytes(genesisBytes); err != nil {
+			return fmt.Errorf("input genesis failed verification: %w", err)
+		}
+	}
+
 	// Parse the genesis doc
 	var genesisDoc GenesisDoc
 	err = json.Unmarshal(genesisBytes, &genesisDoc)
@@ -75,23 +230,74 @@ func processGenesisFile(inputFile, outputFile string) error {
 		return fmt.Errorf("error parsing genesis JSON: %v", err)
 	}
 
-	// Convert app_state JSON to map
-	var appState map[string]interface{}
-	err = json.Unmarshal(genesisDoc.AppState, &appState)
+	// Split app_state into its per-module sub-trees so modules that need to
+	// change fields together (bank balances, staking operator vs. consensus
+	// addresses, ...) can be handled with knowledge of their schema.
+	var rawAppState map[string]json.RawMessage
+	err = json.Unmarshal(genesisDoc.AppState, &rawAppState)
 	if err != nil {
 		return fmt.Errorf("error parsing app_state JSON: %v", err)
 	}
 
-	// Process the app state - recursively replace all unicorn prefixes with esim
-	processAppState(appState)
+	var mf *manifest.Manifest
+	if opts.DryRun || opts.ManifestPath != "" {
+		mf = manifest.New()
+	}
+
+	registry := modules.NewRegistry()
+	for _, name := range strings.Split(opts.DisabledModules, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			registry.Disable(name)
+		}
+	}
+	hrps := modules.HRPSetFromConfig(opts.Config)
+	if err := registry.Apply(rawAppState, hrps, mf); err != nil {
+		return fmt.Errorf("error applying module transformers: %v", err)
+	}
+
+	// Fall back to the generic recursive string walk for modules without a
+	// dedicated transformer, so addresses in unmodeled state (e.g. genutil
+	// gentxs) still get converted.
+	for key, raw := range rawAppState {
+		if registry.Handles(key) {
+			continue
+		}
+		var generic interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			continue
+		}
+		processAppState(generic, opts.Config, "/app_state/"+key, key, mf)
+		updated, err := json.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("error marshalling %s state: %v", key, err)
+		}
+		rawAppState[key] = updated
+	}
+
+	// Update chain ID if it contains the old account prefix
+	if strings.Contains(genesisDoc.ChainID, opts.Config.Account.From) {
+		genesisDoc.ChainID = strings.ReplaceAll(genesisDoc.ChainID, opts.Config.Account.From, opts.Config.Account.To)
+	}
 
-	// Update chain ID if it contains the old prefix
-	if strings.Contains(genesisDoc.ChainID, OldPrefix) {
-		genesisDoc.ChainID = strings.ReplaceAll(genesisDoc.ChainID, OldPrefix, NewPrefix)
+	if mf != nil {
+		if collisions := mf.Collisions(); len(collisions) > 0 && !opts.AllowMerges {
+			return fmt.Errorf("detected %d colliding converted address(es), pass -allow-merges to proceed: %v", len(collisions), collisions)
+		}
+		if opts.ManifestPath != "" {
+			if err := mf.Write(opts.ManifestPath); err != nil {
+				return fmt.Errorf("error writing manifest: %v", err)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Dry run: would have converted addresses from %s to %s (no output written)\n", opts.Config.Account.From, opts.Config.Account.To)
+		return nil
 	}
 
 	// Convert the updated app state back to JSON
-	updatedAppState, err := json.Marshal(appState)
+	updatedAppState, err := json.Marshal(rawAppState)
 	if err != nil {
 		return fmt.Errorf("error marshalling updated app_state: %v", err)
 	}
@@ -105,68 +311,125 @@ func processGenesisFile(inputFile, outputFile string) error {
 		return fmt.Errorf("error marshalling updated genesis: %v", err)
 	}
 
+	if opts.Verify {
+		if err := verifyGenesisBytes(updatedGenesisBytes); err != nil {
+			return fmt.Errorf("refusing to write output: converted genesis failed verification: %w", err)
+		}
+	}
+
 	// Write the new genesis file
 	err = os.WriteFile(outputFile, updatedGenesisBytes, 0644)
 	if err != nil {
 		return fmt.Errorf("error writing output genesis file: %v", err)
 	}
 
-	fmt.Printf("Successfully converted addresses from %s to %s and saved to %s\n", OldPrefix, NewPrefix, outputFile)
+	fmt.Printf("Successfully converted addresses from %s to %s and saved to %s\n", opts.Config.Account.From, opts.Config.Account.To, outputFile)
 	return nil
 }
 
-// processAppState recursively processes the app state JSON structure
-func processAppState(data interface{}) {
+// processAppState recursively processes the app state JSON structure,
+// rewriting any string carrying one of cfg's configured prefixes. path is
+// the JSON pointer path to data, extended as the walk descends; module
+// identifies the app_state key the walk started from, for manifest entries.
+func processAppState(data interface{}, cfg config.Config, path, module string, mf *manifest.Manifest) {
 	switch v := data.(type) {
 	case map[string]interface{}:
 		// Process each key-value pair in the map
 		for key, value := range v {
+			childPath := manifest.JoinPath(path, key)
+
 			// Convert keys that might contain addresses
-			if strings.Contains(key, OldPrefix) {
-				newKey := replaceAddressInString(key)
+			if cfg.ContainsAnyPrefix(key) {
+				newKey := replaceAddressInString(key, cfg, childPath, module, mf)
 				v[newKey] = value
 				delete(v, key)
 				// Continue processing with the new key
 				key = newKey
+				childPath = manifest.JoinPath(path, key)
 			}
 
 			// Handle string values that might contain addresses
-			if strValue, ok := value.(string); ok && strings.Contains(strValue, OldPrefix) {
-				v[key] = replaceAddressInString(strValue)
+			if strValue, ok := value.(string); ok && cfg.ContainsAnyPrefix(strValue) {
+				v[key] = replaceAddressInString(strValue, cfg, childPath, module, mf)
 			} else {
 				// Process the value recursively
-				processAppState(value)
+				processAppState(value, cfg, childPath, module, mf)
 			}
 		}
 	case []interface{}:
 		// Process each element in the array
 		for i, element := range v {
-			if strElement, ok := element.(string); ok && strings.Contains(strElement, OldPrefix) {
-				v[i] = replaceAddressInString(strElement)
+			childPath := manifest.JoinIndex(path, i)
+			if strElement, ok := element.(string); ok && cfg.ContainsAnyPrefix(strElement) {
+				v[i] = replaceAddressInString(strElement, cfg, childPath, module, mf)
 			} else {
-				processAppState(element)
+				processAppState(element, cfg, childPath, module, mf)
 			}
 		}
 	}
 }
 
-// replaceAddressInString replaces all occurrences of the old prefix in a string
-func replaceAddressInString(text string) string {
-	// Handle factory token addresses (format: factory/unicorn.../token)
-	factoryPattern := regexp.MustCompile(`factory/` + OldPrefix + `([a-zA-Z0-9]+)`)
-	text = factoryPattern.ReplaceAllString(text, "factory/"+NewPrefix+"${1}")
+// bech32CandidatePattern matches candidate bech32 strings: a human-readable
+// part, a single "1" separator, and a data part drawn from the bech32
+// charset. The pattern itself enforces only the 8-character minimum; the
+// 90-character maximum allowed by BIP-173 is checked by
+// replaceAddressInString.
+var bech32CandidatePattern = regexp.MustCompile(`[a-zA-Z0-9]{1,83}1[qpzry9x8gf2tvdw0s3jn54khce6mua7l]{6,}`)
+
+// replaceAddressInString finds bech32 addresses carrying one of cfg's
+// configured prefixes and re-encodes them under the matching target prefix
+// via bech32conv, so the result carries a valid checksum rather than a
+// patched-in-place one. The factory/<addr>/<sub> denom path form is
+// preserved by only re-encoding the address segment, using cfg's dedicated
+// FactoryDenomPrefix mapping (falling back to the account mapping). Every
+// successful conversion is recorded in mf, if non-nil, against path and
+// module.
+func replaceAddressInString(text string, cfg config.Config, path, module string, mf *manifest.Manifest) string {
+	if strings.HasPrefix(text, "factory/") {
+		mapping := cfg.FactoryDenomPrefix
+		if mapping.From == "" {
+			mapping = cfg.Account
+		}
+		parts := strings.Split(text, "/")
+		if len(parts) > 1 && strings.HasPrefix(parts[1], mapping.From) {
+			original := parts[1]
+			if converted, err := bech32conv.ConvertPrefix(original, mapping.To); err == nil {
+				parts[1] = converted
+				recordManifestEntry(mf, original, converted, path, module)
+			}
+		}
+		return strings.Join(parts, "/")
+	}
 
-	// Handle regular bech32 addresses
-	// This is a simplified approach - in a real implementation you would want to use
-	// proper bech32 decoder/encoder to ensure the checksum is valid
-	bech32Pattern := regexp.MustCompile(OldPrefix + `([a-zA-Z0-9]+)`)
-	text = bech32Pattern.ReplaceAllString(text, NewPrefix+"${1}")
+	return bech32CandidatePattern.ReplaceAllStringFunc(text, func(candidate string) string {
+		if len(candidate) < 8 || len(candidate) > 90 {
+			return candidate
+		}
+		for _, mapping := range cfg.Mappings() {
+			if !strings.HasPrefix(candidate, mapping.From) {
+				continue
+			}
+			if converted, err := bech32conv.ConvertPrefix(candidate, mapping.To); err == nil {
+				recordManifestEntry(mf, candidate, converted, path, module)
+				return converted
+			}
+		}
+		return candidate
+	})
+}
 
-	return text
+// recordManifestEntry appends a manifest entry for a converted address when
+// mf is non-nil (i.e. -manifest or -dry-run was requested).
+func recordManifestEntry(mf *manifest.Manifest, original, converted, path, module string) {
+	if mf == nil {
+		return
+	}
+	dataHex, _ := bech32conv.DecodeDataHex(original)
+	mf.Add(manifest.Entry{Original: original, Converted: converted, Path: path, Module: module, DataHex: dataHex})
 }
 
 // processCsvDirectory processes all CSV files in a directory
-func processCsvDirectory(directory string) error {
+func processCsvDirectory(directory string, opts RunOptions) error {
 	fmt.Printf("Processing CSV files in directory: %s\n", directory)
 
 	// Get all CSV files
@@ -175,19 +438,44 @@ func processCsvDirectory(directory string) error {
 		return fmt.Errorf("error finding CSV files: %v", err)
 	}
 
+	var mf *manifest.Manifest
+	if opts.DryRun || opts.ManifestPath != "" {
+		mf = manifest.New()
+	}
+
 	for _, csvFile := range files {
-		err := processCsvFile(csvFile)
+		err := processCsvFile(csvFile, opts, mf)
 		if err != nil {
 			fmt.Printf("Warning: Error processing %s: %v\n", csvFile, err)
 			// Continue with other files
 		}
 	}
 
+	if mf != nil {
+		if collisions := mf.Collisions(); len(collisions) > 0 && !opts.AllowMerges {
+			return fmt.Errorf("detected %d colliding converted address(es) across CSV files, pass -allow-merges to proceed: %v", len(collisions), collisions)
+		}
+		if opts.ManifestPath != "" {
+			if err := mf.Write(csvManifestPath(opts.ManifestPath)); err != nil {
+				return fmt.Errorf("error writing CSV manifest: %v", err)
+			}
+		}
+	}
+
 	return nil
 }
 
-// processCsvFile processes a single CSV file
-func processCsvFile(csvFile string) error {
+// csvManifestPath derives a separate manifest path for CSV conversions, so
+// a single -manifest flag doesn't have the genesis and CSV runs of the same
+// invocation clobber each other's manifest file.
+func csvManifestPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_csv" + ext
+}
+
+// processCsvFile processes a single CSV file. mf, if non-nil, accumulates a
+// manifest entry for every address converted across the whole directory.
+func processCsvFile(csvFile string, opts RunOptions, mf *manifest.Manifest) error {
 	// Open the CSV file
 	file, err := os.Open(csvFile)
 	if err != nil {
@@ -212,8 +500,9 @@ func processCsvFile(csvFile string) error {
 	modified := false
 	for i, record := range records {
 		for j, field := range record {
-			if strings.Contains(field, OldPrefix) {
-				records[i][j] = replaceAddressInString(field)
+			if opts.Config.ContainsAnyPrefix(field) {
+				path := fmt.Sprintf("%s:row %d, column %d", csvFile, i, j)
+				records[i][j] = replaceAddressInString(field, opts.Config, path, "csv", mf)
 				modified = true
 			}
 		}
@@ -225,8 +514,13 @@ func processCsvFile(csvFile string) error {
 		return nil
 	}
 
+	if opts.DryRun {
+		fmt.Printf("Dry run: would have converted addresses in %s (no output written)\n", csvFile)
+		return nil
+	}
+
 	// Create the output filename
-	outputFile := strings.TrimSuffix(csvFile, ".csv") + "_" + NewPrefix + ".csv"
+	outputFile := strings.TrimSuffix(csvFile, ".csv") + "_" + opts.Config.Account.To + ".csv"
 
 	// Create the output file
 	outFile, err := os.Create(outputFile)
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/faddat/horse/snapshot/config"
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+func TestReplaceAddressInStringPicksMatchingHRPFamily(t *testing.T) {
+	cfg := config.DeriveFromAccountPrefix("unicorn", "esim")
+
+	cases := []struct {
+		name string
+		addr string
+		want string
+	}{
+		{"account", "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh", "esim1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnn0w2fx"},
+		// Account.From ("unicorn") is itself a prefix of ValOper.From
+		// ("unicornvaloper") and ValCons.From ("unicornvalcons"), so the
+		// candidate scanner has to try the longest prefix first or it
+		// re-encodes these under the plain account HRP instead of their own
+		// family.
+		{"valoper", "unicornvaloper1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnqmwxzr", "esimvaloper1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnvvy8xp"},
+		{"valcons", "unicornvalcons1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn5ga6wz", "esimvalcons1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnclhm2q"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := replaceAddressInString(c.addr, cfg, "/path", "module", nil); got != c.want {
+				t.Errorf("replaceAddressInString(%q) = %q, want %q", c.addr, got, c.want)
+			}
+		})
+	}
+}
+
+func TestReplaceAddressInStringFactoryDenom(t *testing.T) {
+	cfg := config.DeriveFromAccountPrefix("unicorn", "esim")
+	mf := manifest.New()
+
+	addr := "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh"
+	text := "factory/" + addr + "/mytoken"
+	want := "factory/esim1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnn0w2fx/mytoken"
+
+	if got := replaceAddressInString(text, cfg, "/path", "module", mf); got != want {
+		t.Errorf("replaceAddressInString(%q) = %q, want %q", text, got, want)
+	}
+	if len(mf.Entries) != 1 || mf.Entries[0].Original != addr {
+		t.Errorf("manifest entries = %+v, want one entry for %q", mf.Entries, addr)
+	}
+}
+
+func TestProcessAppState(t *testing.T) {
+	cfg := config.DeriveFromAccountPrefix("unicorn", "esim")
+	mf := manifest.New()
+
+	unicornAddr := "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh"
+	esimAddr := "esim1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnn0w2fx"
+
+	data := map[string]interface{}{
+		"some_address": unicornAddr,
+		"nested": map[string]interface{}{
+			"addresses": []interface{}{unicornAddr, "no-prefix-here"},
+		},
+	}
+
+	processAppState(data, cfg, "/app_state/genutil", "genutil", mf)
+
+	if data["some_address"] != esimAddr {
+		t.Errorf("some_address = %v, want %v", data["some_address"], esimAddr)
+	}
+	nested := data["nested"].(map[string]interface{})
+	addresses := nested["addresses"].([]interface{})
+	if addresses[0] != esimAddr {
+		t.Errorf("addresses[0] = %v, want %v", addresses[0], esimAddr)
+	}
+	if addresses[1] != "no-prefix-here" {
+		t.Errorf("addresses[1] = %v, want unchanged", addresses[1])
+	}
+	if len(mf.Entries) != 2 {
+		t.Fatalf("len(mf.Entries) = %d, want 2", len(mf.Entries))
+	}
+	for _, e := range mf.Entries {
+		if e.Module != "genutil" {
+			t.Errorf("entry module = %q, want %q", e.Module, "genutil")
+		}
+	}
+}
+
+func TestGenesisDocRoundTripPreservesExtraFields(t *testing.T) {
+	input := []byte(`{
+		"app_state": {"bank": {}},
+		"chain_id": "unicorn-1",
+		"genesis_time": "2024-01-01T00:00:00Z",
+		"consensus_params": {},
+		"initial_height": "1",
+		"validators": [],
+		"app_hash": "deadbeef"
+	}`)
+
+	var doc GenesisDoc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if doc.ChainID != "unicorn-1" {
+		t.Errorf("ChainID = %q, want %q", doc.ChainID, "unicorn-1")
+	}
+	if string(doc.Extra["app_hash"]) != `"deadbeef"` {
+		t.Errorf("Extra[\"app_hash\"] = %s, want %q", doc.Extra["app_hash"], `"deadbeef"`)
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var roundTripped map[string]interface{}
+	if err := json.Unmarshal(out, &roundTripped); err != nil {
+		t.Fatalf("unmarshalling round-tripped JSON: %v", err)
+	}
+	if roundTripped["app_hash"] != "deadbeef" {
+		t.Errorf("round-tripped app_hash = %v, want %q (field not modeled by GenesisDoc must survive)", roundTripped["app_hash"], "deadbeef")
+	}
+	if roundTripped["chain_id"] != "unicorn-1" {
+		t.Errorf("round-tripped chain_id = %v, want %q", roundTripped["chain_id"], "unicorn-1")
+	}
+}
+
+func TestVerifyGenesisBytes(t *testing.T) {
+	if err := verifyGenesisBytes([]byte(`{"chain_id":"test-1"}`)); err != nil {
+		t.Errorf("verifyGenesisBytes returned error for a valid genesis doc: %v", err)
+	}
+
+	if err := verifyGenesisBytes([]byte(`{}`)); err == nil {
+		t.Error("verifyGenesisBytes succeeded for a genesis doc with no chain_id, want error")
+	}
+}
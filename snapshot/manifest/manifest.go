@@ -0,0 +1,120 @@
+// Package manifest records every address a genesis or CSV conversion
+// rewrites, so operators can diff the run against an expected mapping and
+// catch collisions before submitting the result anywhere.
+package manifest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Entry records one address rewritten during a conversion run.
+type Entry struct {
+	Original  string `json:"original"`
+	Converted string `json:"converted"`
+	Path      string `json:"path"`
+	Module    string `json:"module"`
+	DataHex   string `json:"data_hex,omitempty"`
+}
+
+// Manifest accumulates Entry records for a single conversion run.
+type Manifest struct {
+	Entries []Entry
+}
+
+// New returns an empty Manifest.
+func New() *Manifest {
+	return &Manifest{}
+}
+
+// Add records e. Entries with an empty Converted (a decode failure) are
+// still recorded, since operators need to see what the tool couldn't
+// convert, but they're excluded from collision detection.
+func (m *Manifest) Add(e Entry) {
+	m.Entries = append(m.Entries, e)
+}
+
+// Collisions returns, for every converted address that more than one
+// distinct original address mapped onto, the sorted list of originals that
+// collided.
+func (m *Manifest) Collisions() map[string][]string {
+	byConverted := map[string]map[string]bool{}
+	for _, e := range m.Entries {
+		if e.Converted == "" {
+			continue
+		}
+		if byConverted[e.Converted] == nil {
+			byConverted[e.Converted] = map[string]bool{}
+		}
+		byConverted[e.Converted][e.Original] = true
+	}
+
+	collisions := map[string][]string{}
+	for converted, originals := range byConverted {
+		if len(originals) < 2 {
+			continue
+		}
+		list := make([]string, 0, len(originals))
+		for o := range originals {
+			list = append(list, o)
+		}
+		sort.Strings(list)
+		collisions[converted] = list
+	}
+	return collisions
+}
+
+// Write writes the manifest to path, choosing CSV or JSON by extension
+// (".csv" for CSV, anything else for JSON).
+func (m *Manifest) Write(path string) error {
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		return m.writeCSV(path)
+	}
+	return m.writeJSON(path)
+}
+
+func (m *Manifest) writeJSON(path string) error {
+	data, err := json.MarshalIndent(m.Entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing manifest file: %w", err)
+	}
+	return nil
+}
+
+func (m *Manifest) writeCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating manifest file: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"original", "converted", "path", "module", "data_hex"}); err != nil {
+		return fmt.Errorf("writing manifest header: %w", err)
+	}
+	for _, e := range m.Entries {
+		if err := w.Write([]string{e.Original, e.Converted, e.Path, e.Module, e.DataHex}); err != nil {
+			return fmt.Errorf("writing manifest row: %w", err)
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// JoinPath appends a field or index segment to a JSON pointer path.
+func JoinPath(path, segment string) string {
+	return path + "/" + segment
+}
+
+// JoinIndex appends an array index segment to a JSON pointer path.
+func JoinIndex(path string, index int) string {
+	return JoinPath(path, strconv.Itoa(index))
+}
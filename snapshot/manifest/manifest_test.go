@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	m := New()
+	m.Add(Entry{Original: "unicorn1abc", Converted: "esim1abc", Path: "/app_state/bank/balances/0/address", Module: "bank"})
+	m.Add(Entry{Original: "unicorn1def", Converted: "", Path: "/app_state/bank/balances/1/address", Module: "bank"})
+
+	if len(m.Entries) != 2 {
+		t.Fatalf("len(m.Entries) = %d, want 2", len(m.Entries))
+	}
+	if m.Entries[1].Converted != "" {
+		t.Errorf("Entries[1].Converted = %q, want empty (decode failure still recorded)", m.Entries[1].Converted)
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	cases := []struct {
+		name    string
+		entries []Entry
+		want    map[string][]string
+	}{
+		{
+			name: "no collisions",
+			entries: []Entry{
+				{Original: "unicorn1a", Converted: "esim1a"},
+				{Original: "unicorn1b", Converted: "esim1b"},
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "two originals converge on one converted address",
+			entries: []Entry{
+				{Original: "unicorn1a", Converted: "esim1merged"},
+				{Original: "unicorn1b", Converted: "esim1merged"},
+			},
+			want: map[string][]string{"esim1merged": {"unicorn1a", "unicorn1b"}},
+		},
+		{
+			name: "repeated entry for the same original is not a collision",
+			entries: []Entry{
+				{Original: "unicorn1a", Converted: "esim1a"},
+				{Original: "unicorn1a", Converted: "esim1a"},
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "decode failures (empty Converted) are excluded",
+			entries: []Entry{
+				{Original: "unicorn1a", Converted: ""},
+				{Original: "unicorn1b", Converted: ""},
+			},
+			want: map[string][]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := New()
+			for _, e := range c.entries {
+				m.Add(e)
+			}
+			got := m.Collisions()
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Collisions() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	m := New()
+	m.Add(Entry{Original: "unicorn1a", Converted: "esim1a", Path: "/app_state/bank/balances/0/address", Module: "bank", DataHex: "abcd"})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.json")
+	if err := m.Write(path); err != nil {
+		t.Fatalf("Write(%q) returned error: %v", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading manifest file: %v", err)
+	}
+	if !strings.Contains(string(data), `"original": "unicorn1a"`) || !strings.Contains(string(data), `"converted": "esim1a"`) {
+		t.Errorf("manifest JSON = %s, missing expected fields", data)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	m := New()
+	m.Add(Entry{Original: "unicorn1a", Converted: "esim1a", Path: "/app_state/bank/balances/0/address", Module: "bank", DataHex: "abcd"})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.csv")
+	if err := m.Write(path); err != nil {
+		t.Fatalf("Write(%q) returned error: %v", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading manifest file: %v", err)
+	}
+	want := "original,converted,path,module,data_hex\nunicorn1a,esim1a,/app_state/bank/balances/0/address,bank,abcd\n"
+	if string(data) != want {
+		t.Errorf("manifest CSV = %q, want %q", data, want)
+	}
+}
+
+func TestJoinPathAndJoinIndex(t *testing.T) {
+	got := JoinPath("/app_state", "bank")
+	if want := "/app_state/bank"; got != want {
+		t.Errorf("JoinPath = %q, want %q", got, want)
+	}
+
+	got = JoinIndex("/app_state/bank/balances", 2)
+	if want := "/app_state/bank/balances/2"; got != want {
+		t.Errorf("JoinIndex = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// AuthTransformer rewrites every "address" field under auth.accounts,
+// including addresses nested inside vesting account wrappers.
+type AuthTransformer struct{}
+
+func (AuthTransformer) Name() string { return "auth" }
+
+func (AuthTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing auth state: %w", err)
+	}
+
+	rewriteAddressFields(state["accounts"], map[string]HRPPair{"address": hrps.Account}, path+"/accounts", "auth", mf)
+
+	return json.Marshal(state)
+}
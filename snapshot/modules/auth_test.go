@@ -0,0 +1,24 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAuthTransformer(t *testing.T) {
+	raw := json.RawMessage(`{"accounts":[{"@type":"/cosmos.auth.v1beta1.BaseAccount","address":"` + unicornAddr + `"}]}`)
+
+	out, err := (AuthTransformer{}).Transform(raw, testHRPSet(), "/app_state/auth", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+	accounts := state["accounts"].([]interface{})
+	if got := accounts[0].(map[string]interface{})["address"]; got != esimAddr {
+		t.Errorf("address = %v, want %v", got, esimAddr)
+	}
+}
@@ -0,0 +1,103 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// BankTransformer rewrites bank.balances, converting each balance's address
+// and merging balances that collide onto the same address after conversion
+// by summing their coins.
+type BankTransformer struct{}
+
+func (BankTransformer) Name() string { return "bank" }
+
+func (BankTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing bank state: %w", err)
+	}
+
+	if balances, ok := state["balances"].([]interface{}); ok {
+		state["balances"] = mergeBalances(balances, hrps.Account, path+"/balances", mf)
+	}
+
+	return json.Marshal(state)
+}
+
+// mergeBalances converts each balance's address and, for balances that
+// collide onto the same post-conversion address, sums their coins rather
+// than letting the later one silently overwrite the earlier.
+func mergeBalances(balances []interface{}, pair HRPPair, path string, mf *manifest.Manifest) []interface{} {
+	order := make([]string, 0, len(balances))
+	merged := make(map[string]map[string]interface{}, len(balances))
+
+	for i, b := range balances {
+		balance, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		addr, _ := balance["address"].(string)
+		balance["address"] = convertAddress(addr, pair, manifest.JoinIndex(path, i)+"/address", "bank", mf)
+		newAddr := balance["address"].(string)
+
+		existing, seen := merged[newAddr]
+		if !seen {
+			merged[newAddr] = balance
+			order = append(order, newAddr)
+			continue
+		}
+		existing["coins"] = mergeCoins(existing["coins"], balance["coins"])
+	}
+
+	result := make([]interface{}, 0, len(order))
+	for _, addr := range order {
+		result = append(result, merged[addr])
+	}
+	return result
+}
+
+// mergeCoins sums the amounts of coins sharing a denom across two coin
+// lists, in the order denoms were first seen.
+func mergeCoins(a, b interface{}) []interface{} {
+	amounts := map[string]*big.Int{}
+	order := make([]string, 0)
+
+	add := func(coins interface{}) {
+		list, ok := coins.([]interface{})
+		if !ok {
+			return
+		}
+		for _, c := range list {
+			coin, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			denom, _ := coin["denom"].(string)
+			amountStr, _ := coin["amount"].(string)
+			amount, ok := new(big.Int).SetString(amountStr, 10)
+			if !ok {
+				continue
+			}
+			if _, exists := amounts[denom]; !exists {
+				order = append(order, denom)
+				amounts[denom] = big.NewInt(0)
+			}
+			amounts[denom].Add(amounts[denom], amount)
+		}
+	}
+	add(a)
+	add(b)
+
+	result := make([]interface{}, 0, len(order))
+	for _, denom := range order {
+		result = append(result, map[string]interface{}{
+			"denom":  denom,
+			"amount": amounts[denom].String(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBankTransformerConvertsAddress(t *testing.T) {
+	raw := json.RawMessage(`{"balances":[{"address":"` + unicornAddr + `","coins":[{"denom":"uesim","amount":"100"}]}]}`)
+
+	out, err := (BankTransformer{}).Transform(raw, testHRPSet(), "/app_state/bank", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+	balances := state["balances"].([]interface{})
+	if len(balances) != 1 {
+		t.Fatalf("len(balances) = %d, want 1", len(balances))
+	}
+	if got := balances[0].(map[string]interface{})["address"]; got != esimAddr {
+		t.Errorf("address = %v, want %v", got, esimAddr)
+	}
+}
+
+func TestMergeBalancesCollision(t *testing.T) {
+	balances := []interface{}{
+		map[string]interface{}{
+			"address": unicornAddr,
+			"coins": []interface{}{
+				map[string]interface{}{"denom": "uesim", "amount": "100"},
+			},
+		},
+		map[string]interface{}{
+			"address": unicornAddr,
+			"coins": []interface{}{
+				map[string]interface{}{"denom": "uesim", "amount": "50"},
+				map[string]interface{}{"denom": "uatom", "amount": "7"},
+			},
+		},
+	}
+
+	got := mergeBalances(balances, testHRPSet().Account, "/app_state/bank/balances", nil)
+	if len(got) != 1 {
+		t.Fatalf("len(got) = %d, want 1 (colliding balances should merge)", len(got))
+	}
+
+	merged := got[0].(map[string]interface{})
+	if merged["address"] != esimAddr {
+		t.Fatalf("address = %v, want %v", merged["address"], esimAddr)
+	}
+
+	coins := merged["coins"].([]interface{})
+	amounts := map[string]string{}
+	for _, c := range coins {
+		coin := c.(map[string]interface{})
+		amounts[coin["denom"].(string)] = coin["amount"].(string)
+	}
+	if amounts["uesim"] != "150" {
+		t.Errorf(`amounts["uesim"] = %q, want "150"`, amounts["uesim"])
+	}
+	if amounts["uatom"] != "7" {
+		t.Errorf(`amounts["uatom"] = %q, want "7"`, amounts["uatom"])
+	}
+}
+
+func TestMergeBalancesNoCollision(t *testing.T) {
+	otherAddr := "unicorn1pqqsyqcyq5rqwzqfpg9scrgwpugpzysn6sw8vs"
+	balances := []interface{}{
+		map[string]interface{}{
+			"address": unicornAddr,
+			"coins":   []interface{}{map[string]interface{}{"denom": "uesim", "amount": "100"}},
+		},
+		map[string]interface{}{
+			"address": otherAddr,
+			"coins":   []interface{}{map[string]interface{}{"denom": "uesim", "amount": "50"}},
+		},
+	}
+
+	got := mergeBalances(balances, testHRPSet().Account, "/app_state/bank/balances", nil)
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2 (distinct addresses should not merge)", len(got))
+	}
+}
+
+func TestMergeCoins(t *testing.T) {
+	a := []interface{}{map[string]interface{}{"denom": "uesim", "amount": "100"}}
+	b := []interface{}{
+		map[string]interface{}{"denom": "uesim", "amount": "50"},
+		map[string]interface{}{"denom": "uatom", "amount": "7"},
+	}
+
+	got := mergeCoins(a, b)
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if got[0].(map[string]interface{})["denom"] != "uesim" || got[0].(map[string]interface{})["amount"] != "150" {
+		t.Errorf("got[0] = %+v, want uesim 150 (denom order preserved from first sighting)", got[0])
+	}
+	if got[1].(map[string]interface{})["denom"] != "uatom" || got[1].(map[string]interface{})["amount"] != "7" {
+		t.Errorf("got[1] = %+v, want uatom 7", got[1])
+	}
+}
@@ -0,0 +1,39 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// DistributionTransformer rewrites distribution's delegator withdraw
+// addresses, the validator-keyed reward records, and the previous block
+// proposer's consensus address.
+type DistributionTransformer struct{}
+
+func (DistributionTransformer) Name() string { return "distribution" }
+
+func (DistributionTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing distribution state: %w", err)
+	}
+
+	if proposer, ok := state["previous_proposer"].(string); ok {
+		state["previous_proposer"] = convertAddress(proposer, hrps.ValCons, path+"/previous_proposer", "distribution", mf)
+	}
+
+	rewriteAddressFields(state["delegator_withdraw_infos"], map[string]HRPPair{
+		"delegator_address": hrps.Account,
+		"withdraw_address":  hrps.Account,
+	}, path+"/delegator_withdraw_infos", "distribution", mf)
+	valoperFields := map[string]HRPPair{"validator_address": hrps.ValOper}
+	rewriteAddressFields(state["outstanding_rewards"], valoperFields, path+"/outstanding_rewards", "distribution", mf)
+	rewriteAddressFields(state["validator_accumulated_commissions"], valoperFields, path+"/validator_accumulated_commissions", "distribution", mf)
+	rewriteAddressFields(state["validator_historical_rewards"], valoperFields, path+"/validator_historical_rewards", "distribution", mf)
+	rewriteAddressFields(state["validator_current_rewards"], valoperFields, path+"/validator_current_rewards", "distribution", mf)
+	rewriteAddressFields(state["validator_slash_events"], valoperFields, path+"/validator_slash_events", "distribution", mf)
+
+	return json.Marshal(state)
+}
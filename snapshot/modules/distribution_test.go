@@ -0,0 +1,35 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDistributionTransformer(t *testing.T) {
+	raw := json.RawMessage(`{
+		"previous_proposer": "` + unicornValconsAddr + `",
+		"delegator_withdraw_infos": [{"delegator_address": "` + unicornAddr + `", "withdraw_address": "` + unicornAddr + `"}]
+	}`)
+
+	out, err := (DistributionTransformer{}).Transform(raw, testHRPSet(), "/app_state/distribution", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+
+	if got := state["previous_proposer"]; got != esimValconsAddr {
+		t.Errorf("previous_proposer = %v, want %v", got, esimValconsAddr)
+	}
+
+	info := state["delegator_withdraw_infos"].([]interface{})[0].(map[string]interface{})
+	if got := info["delegator_address"]; got != esimAddr {
+		t.Errorf("delegator_address = %v, want %v", got, esimAddr)
+	}
+	if got := info["withdraw_address"]; got != esimAddr {
+		t.Errorf("withdraw_address = %v, want %v", got, esimAddr)
+	}
+}
@@ -0,0 +1,26 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// GovTransformer rewrites the depositor and voter addresses recorded in
+// gov's deposits and votes.
+type GovTransformer struct{}
+
+func (GovTransformer) Name() string { return "gov" }
+
+func (GovTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing gov state: %w", err)
+	}
+
+	rewriteAddressFields(state["deposits"], map[string]HRPPair{"depositor": hrps.Account}, path+"/deposits", "gov", mf)
+	rewriteAddressFields(state["votes"], map[string]HRPPair{"voter": hrps.Account}, path+"/votes", "gov", mf)
+
+	return json.Marshal(state)
+}
@@ -0,0 +1,30 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGovTransformer(t *testing.T) {
+	raw := json.RawMessage(`{
+		"deposits": [{"depositor": "` + unicornAddr + `"}],
+		"votes": [{"voter": "` + unicornAddr + `"}]
+	}`)
+
+	out, err := (GovTransformer{}).Transform(raw, testHRPSet(), "/app_state/gov", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+
+	if got := state["deposits"].([]interface{})[0].(map[string]interface{})["depositor"]; got != esimAddr {
+		t.Errorf("depositor = %v, want %v", got, esimAddr)
+	}
+	if got := state["votes"].([]interface{})[0].(map[string]interface{})["voter"]; got != esimAddr {
+		t.Errorf("voter = %v, want %v", got, esimAddr)
+	}
+}
@@ -0,0 +1,155 @@
+// Package modules rewrites the per-module sub-trees of a Cosmos SDK genesis
+// app_state. Fields that must change together - colliding bank balances,
+// validator operator vs. consensus addresses, signing infos keyed by
+// consensus address - are handled with knowledge of the module's schema
+// instead of a blind string walk over the whole tree.
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/faddat/horse/snapshot/bech32conv"
+	"github.com/faddat/horse/snapshot/config"
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// HRPPair is the old and new human-readable prefix for one address family.
+type HRPPair struct {
+	Old string
+	New string
+}
+
+// HRPSet holds the HRP families a Cosmos SDK chain derives from a single
+// account prefix.
+type HRPSet struct {
+	Account HRPPair
+	ValOper HRPPair
+	ValCons HRPPair
+}
+
+// HRPSetFromConfig builds an HRPSet from a loaded Config's declared prefix
+// mappings.
+func HRPSetFromConfig(cfg config.Config) HRPSet {
+	return HRPSet{
+		Account: HRPPair{Old: cfg.Account.From, New: cfg.Account.To},
+		ValOper: HRPPair{Old: cfg.ValOper.From, New: cfg.ValOper.To},
+		ValCons: HRPPair{Old: cfg.ValCons.From, New: cfg.ValCons.To},
+	}
+}
+
+// Transformer rewrites one module's app_state sub-tree. path is the JSON
+// pointer to the module's sub-state (e.g. "/app_state/bank"); if mf is
+// non-nil, every address the transformer converts should be recorded
+// against it for the run's manifest.
+type Transformer interface {
+	// Name is the app_state key the transformer handles, e.g. "bank".
+	Name() string
+	// Transform returns the rewritten sub-state.
+	Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error)
+}
+
+// Registry drives the set of known transformers over a genesis app_state.
+type Registry struct {
+	transformers []Transformer
+	disabled     map[string]bool
+}
+
+// NewRegistry returns a Registry with every known module transformer
+// enabled.
+func NewRegistry() *Registry {
+	return &Registry{
+		transformers: []Transformer{
+			AuthTransformer{},
+			BankTransformer{},
+			StakingTransformer{},
+			DistributionTransformer{},
+			GovTransformer{},
+			SlashingTransformer{},
+			WasmTransformer{},
+		},
+		disabled: map[string]bool{},
+	}
+}
+
+// Disable turns off the named transformer, e.g. for a -modules flag that
+// excludes modules the operator doesn't run.
+func (r *Registry) Disable(name string) {
+	r.disabled[name] = true
+}
+
+// Handles reports whether name is a known, enabled transformer.
+func (r *Registry) Handles(name string) bool {
+	if r.disabled[name] {
+		return false
+	}
+	for _, t := range r.transformers {
+		if t.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply runs every enabled transformer whose module is present in appState,
+// replacing its sub-state in place. If mf is non-nil, every address a
+// transformer converts is recorded against it.
+func (r *Registry) Apply(appState map[string]json.RawMessage, hrps HRPSet, mf *manifest.Manifest) error {
+	for _, t := range r.transformers {
+		if r.disabled[t.Name()] {
+			continue
+		}
+		raw, ok := appState[t.Name()]
+		if !ok {
+			continue
+		}
+		updated, err := t.Transform(raw, hrps, "/app_state/"+t.Name(), mf)
+		if err != nil {
+			return fmt.Errorf("transforming %s state: %w", t.Name(), err)
+		}
+		appState[t.Name()] = updated
+	}
+	return nil
+}
+
+// convertAddress converts addr from pair's old HRP to its new one, leaving
+// addr untouched if it doesn't carry that prefix or doesn't decode as
+// bech32. If mf is non-nil and the conversion succeeds, it's recorded
+// against path and module.
+func convertAddress(addr string, pair HRPPair, path, module string, mf *manifest.Manifest) string {
+	if addr == "" || !strings.HasPrefix(addr, pair.Old) {
+		return addr
+	}
+	converted, err := bech32conv.ConvertPrefix(addr, pair.New)
+	if err != nil {
+		return addr
+	}
+	if mf != nil {
+		dataHex, _ := bech32conv.DecodeDataHex(addr)
+		mf.Add(manifest.Entry{Original: addr, Converted: converted, Path: path, Module: module, DataHex: dataHex})
+	}
+	return converted
+}
+
+// rewriteAddressFields walks v recursively and, for every object field whose
+// key is present in fields, converts its string value using the paired HRP.
+func rewriteAddressFields(v interface{}, fields map[string]HRPPair, path, module string, mf *manifest.Manifest) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			childPath := manifest.JoinPath(path, k)
+			if pair, ok := fields[k]; ok {
+				if s, ok := val.(string); ok {
+					t[k] = convertAddress(s, pair, childPath, module, mf)
+					continue
+				}
+			}
+			rewriteAddressFields(val, fields, childPath, module, mf)
+		}
+	case []interface{}:
+		for i, item := range t {
+			rewriteAddressFields(item, fields, manifest.JoinIndex(path, i), module, mf)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/faddat/horse/snapshot/config"
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+const (
+	unicornAddr = "unicorn1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh"
+	esimAddr    = "esim1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnn0w2fx"
+
+	unicornValoperAddr = "unicornvaloper1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnqmwxzr"
+	esimValoperAddr    = "esimvaloper1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnvvy8xp"
+
+	unicornValconsAddr = "unicornvalcons1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn5ga6wz"
+	esimValconsAddr    = "esimvalcons1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnclhm2q"
+)
+
+func testHRPSet() HRPSet {
+	return HRPSetFromConfig(config.DeriveFromAccountPrefix("unicorn", "esim"))
+}
+
+func TestHRPSetFromConfig(t *testing.T) {
+	got := testHRPSet()
+	want := HRPSet{
+		Account: HRPPair{Old: "unicorn", New: "esim"},
+		ValOper: HRPPair{Old: "unicornvaloper", New: "esimvaloper"},
+		ValCons: HRPPair{Old: "unicornvalcons", New: "esimvalcons"},
+	}
+	if got != want {
+		t.Errorf("HRPSetFromConfig = %+v, want %+v", got, want)
+	}
+}
+
+func TestConvertAddress(t *testing.T) {
+	cases := []struct {
+		name string
+		addr string
+		pair HRPPair
+		want string
+	}{
+		{"matching prefix converts", unicornAddr, testHRPSet().Account, esimAddr},
+		{"empty address is left alone", "", testHRPSet().Account, ""},
+		{"non-matching prefix is left alone", "cosmos1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh", testHRPSet().Account, "cosmos1qqqsyqcyq5rqwzqfpg9scrgwpugpzysn93utrh"},
+		{"invalid bech32 is left alone", "unicorn1notvalid", testHRPSet().Account, "unicorn1notvalid"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := convertAddress(c.addr, c.pair, "/path", "module", nil); got != c.want {
+				t.Errorf("convertAddress(%q) = %q, want %q", c.addr, got, c.want)
+			}
+		})
+	}
+}
+
+func TestConvertAddressRecordsManifestEntry(t *testing.T) {
+	mf := manifest.New()
+	got := convertAddress(unicornAddr, testHRPSet().Account, "/app_state/bank/balances/0/address", "bank", mf)
+	if got != esimAddr {
+		t.Fatalf("convertAddress = %q, want %q", got, esimAddr)
+	}
+	if len(mf.Entries) != 1 {
+		t.Fatalf("len(mf.Entries) = %d, want 1", len(mf.Entries))
+	}
+	entry := mf.Entries[0]
+	if entry.Original != unicornAddr || entry.Converted != esimAddr || entry.Module != "bank" {
+		t.Errorf("recorded entry = %+v, want original/converted/module matching the conversion", entry)
+	}
+}
+
+func TestRewriteAddressFields(t *testing.T) {
+	state := map[string]interface{}{
+		"delegator_address": unicornAddr,
+		"validator_address": unicornValoperAddr,
+		"unrelated":         "leave me alone",
+		"nested": map[string]interface{}{
+			"delegator_address": unicornAddr,
+		},
+	}
+	fields := map[string]HRPPair{
+		"delegator_address": testHRPSet().Account,
+		"validator_address": testHRPSet().ValOper,
+	}
+
+	rewriteAddressFields(state, fields, "/app_state/staking", "staking", nil)
+
+	if state["delegator_address"] != esimAddr {
+		t.Errorf("delegator_address = %v, want %v", state["delegator_address"], esimAddr)
+	}
+	if state["validator_address"] != esimValoperAddr {
+		t.Errorf("validator_address = %v, want %v", state["validator_address"], esimValoperAddr)
+	}
+	if state["unrelated"] != "leave me alone" {
+		t.Errorf("unrelated = %v, want unchanged", state["unrelated"])
+	}
+	nested := state["nested"].(map[string]interface{})
+	if nested["delegator_address"] != esimAddr {
+		t.Errorf("nested delegator_address = %v, want %v", nested["delegator_address"], esimAddr)
+	}
+}
+
+func TestRegistryDisableAndHandles(t *testing.T) {
+	r := NewRegistry()
+	if !r.Handles("bank") {
+		t.Fatalf("Handles(%q) = false, want true before Disable", "bank")
+	}
+	r.Disable("bank")
+	if r.Handles("bank") {
+		t.Errorf("Handles(%q) = true, want false after Disable", "bank")
+	}
+	if r.Handles("nonexistent") {
+		t.Errorf(`Handles("nonexistent") = true, want false`)
+	}
+}
+
+func TestRegistryApply(t *testing.T) {
+	appState := map[string]json.RawMessage{
+		"bank":  json.RawMessage(`{"balances":[{"address":"` + unicornAddr + `","coins":[{"denom":"uesim","amount":"1"}]}]}`),
+		"other": json.RawMessage(`{"untouched":"` + unicornAddr + `"}`),
+	}
+
+	r := NewRegistry()
+	r.Disable("auth")
+	if err := r.Apply(appState, testHRPSet(), nil); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	var bank map[string]interface{}
+	if err := json.Unmarshal(appState["bank"], &bank); err != nil {
+		t.Fatalf("unmarshalling bank state: %v", err)
+	}
+	balances := bank["balances"].([]interface{})
+	if got := balances[0].(map[string]interface{})["address"]; got != esimAddr {
+		t.Errorf("bank address = %v, want %v", got, esimAddr)
+	}
+
+	// Apply only rewrites modules it has a transformer for; "other" isn't
+	// one and should pass through untouched.
+	var other map[string]interface{}
+	if err := json.Unmarshal(appState["other"], &other); err != nil {
+		t.Fatalf("unmarshalling other state: %v", err)
+	}
+	if other["untouched"] != unicornAddr {
+		t.Errorf("other.untouched = %v, want unchanged", other["untouched"])
+	}
+}
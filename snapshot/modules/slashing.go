@@ -0,0 +1,28 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// SlashingTransformer rewrites slashing's signing_infos and missed_blocks,
+// which are keyed by validator consensus address rather than the account or
+// operator address families.
+type SlashingTransformer struct{}
+
+func (SlashingTransformer) Name() string { return "slashing" }
+
+func (SlashingTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing slashing state: %w", err)
+	}
+
+	consensusFields := map[string]HRPPair{"address": hrps.ValCons}
+	rewriteAddressFields(state["signing_infos"], consensusFields, path+"/signing_infos", "slashing", mf)
+	rewriteAddressFields(state["missed_blocks"], consensusFields, path+"/missed_blocks", "slashing", mf)
+
+	return json.Marshal(state)
+}
@@ -0,0 +1,30 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSlashingTransformer(t *testing.T) {
+	raw := json.RawMessage(`{
+		"signing_infos": [{"address": "` + unicornValconsAddr + `"}],
+		"missed_blocks": [{"address": "` + unicornValconsAddr + `"}]
+	}`)
+
+	out, err := (SlashingTransformer{}).Transform(raw, testHRPSet(), "/app_state/slashing", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+
+	if got := state["signing_infos"].([]interface{})[0].(map[string]interface{})["address"]; got != esimValconsAddr {
+		t.Errorf("signing_infos address = %v, want %v", got, esimValconsAddr)
+	}
+	if got := state["missed_blocks"].([]interface{})[0].(map[string]interface{})["address"]; got != esimValconsAddr {
+		t.Errorf("missed_blocks address = %v, want %v", got, esimValconsAddr)
+	}
+}
@@ -0,0 +1,46 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// StakingTransformer rewrites staking's validators, delegations,
+// unbonding delegations and redelegations. Operator addresses convert under
+// the valoper HRP while delegator addresses convert under the plain account
+// HRP - the two are different address families even though they share an
+// account prefix.
+type StakingTransformer struct{}
+
+func (StakingTransformer) Name() string { return "staking" }
+
+func (StakingTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing staking state: %w", err)
+	}
+
+	rewriteAddressFields(state["validators"], map[string]HRPPair{
+		"operator_address": hrps.ValOper,
+	}, path+"/validators", "staking", mf)
+	rewriteAddressFields(state["delegations"], map[string]HRPPair{
+		"delegator_address": hrps.Account,
+		"validator_address": hrps.ValOper,
+	}, path+"/delegations", "staking", mf)
+	rewriteAddressFields(state["unbonding_delegations"], map[string]HRPPair{
+		"delegator_address": hrps.Account,
+		"validator_address": hrps.ValOper,
+	}, path+"/unbonding_delegations", "staking", mf)
+	rewriteAddressFields(state["redelegations"], map[string]HRPPair{
+		"delegator_address":     hrps.Account,
+		"validator_src_address": hrps.ValOper,
+		"validator_dst_address": hrps.ValOper,
+	}, path+"/redelegations", "staking", mf)
+	rewriteAddressFields(state["last_validator_powers"], map[string]HRPPair{
+		"address": hrps.ValOper,
+	}, path+"/last_validator_powers", "staking", mf)
+
+	return json.Marshal(state)
+}
@@ -0,0 +1,36 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStakingTransformer(t *testing.T) {
+	raw := json.RawMessage(`{
+		"validators": [{"operator_address": "` + unicornValoperAddr + `"}],
+		"delegations": [{"delegator_address": "` + unicornAddr + `", "validator_address": "` + unicornValoperAddr + `"}]
+	}`)
+
+	out, err := (StakingTransformer{}).Transform(raw, testHRPSet(), "/app_state/staking", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+
+	validator := state["validators"].([]interface{})[0].(map[string]interface{})
+	if got := validator["operator_address"]; got != esimValoperAddr {
+		t.Errorf("operator_address = %v, want %v", got, esimValoperAddr)
+	}
+
+	delegation := state["delegations"].([]interface{})[0].(map[string]interface{})
+	if got := delegation["delegator_address"]; got != esimAddr {
+		t.Errorf("delegator_address = %v, want %v", got, esimAddr)
+	}
+	if got := delegation["validator_address"]; got != esimValoperAddr {
+		t.Errorf("validator_address = %v, want %v", got, esimValoperAddr)
+	}
+}
@@ -0,0 +1,29 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// WasmTransformer rewrites the contract, creator and admin addresses
+// recorded in wasm.contracts.
+type WasmTransformer struct{}
+
+func (WasmTransformer) Name() string { return "wasm" }
+
+func (WasmTransformer) Transform(raw json.RawMessage, hrps HRPSet, path string, mf *manifest.Manifest) (json.RawMessage, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("parsing wasm state: %w", err)
+	}
+
+	rewriteAddressFields(state["contracts"], map[string]HRPPair{
+		"contract_address": hrps.Account,
+		"creator":          hrps.Account,
+		"admin":            hrps.Account,
+	}, path+"/contracts", "wasm", mf)
+
+	return json.Marshal(state)
+}
@@ -0,0 +1,29 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWasmTransformer(t *testing.T) {
+	raw := json.RawMessage(`{
+		"contracts": [{"contract_address": "` + unicornAddr + `", "creator": "` + unicornAddr + `", "admin": "` + unicornAddr + `"}]
+	}`)
+
+	out, err := (WasmTransformer{}).Transform(raw, testHRPSet(), "/app_state/wasm", nil)
+	if err != nil {
+		t.Fatalf("Transform returned error: %v", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		t.Fatalf("unmarshalling transformed state: %v", err)
+	}
+
+	contract := state["contracts"].([]interface{})[0].(map[string]interface{})
+	for _, field := range []string{"contract_address", "creator", "admin"} {
+		if got := contract[field]; got != esimAddr {
+			t.Errorf("%s = %v, want %v", field, got, esimAddr)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/faddat/horse/snapshot/config"
+	"github.com/faddat/horse/snapshot/manifest"
+)
+
+// streamGenesisFile converts a genesis file using a token-by-token
+// Decoder/Encoder pipeline instead of loading the whole document into
+// memory, so a multi-GB mainnet genesis doesn't blow past available RAM.
+// Key order is preserved because values are re-emitted as they're read.
+//
+// This path does not run the module-aware transformers in the modules
+// package - it never holds more than the current value in memory, so it
+// can't merge colliding bank balances or derive valoper/valcons HRPs from
+// context. Every string token carrying one of cfg's configured prefixes is
+// rewritten in place instead. Use -streaming=false for the module-aware
+// path on genesis files that fit in memory.
+func streamGenesisFile(inputFile, outputFile string, cfg config.Config) error {
+	fmt.Printf("Streaming genesis file %s -> %s\n", inputFile, outputFile)
+
+	in, err := os.Open(inputFile)
+	if err != nil {
+		return fmt.Errorf("error opening genesis file: %v", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("error creating output genesis file: %v", err)
+	}
+	defer out.Close()
+
+	bufIn := bufio.NewReaderSize(in, 1<<20)
+	bufOut := bufio.NewWriterSize(out, 1<<20)
+
+	dec := json.NewDecoder(bufIn)
+	dec.UseNumber()
+
+	// The streaming path never builds a manifest (see the doc comment
+	// above), so it runs no collision check and -allow-merges has no effect
+	// on it. path and module are still threaded through the walk, mirroring
+	// processAppState, so replaceAddressInString is called with the same
+	// arguments on both paths.
+	if err := streamTransformAny(dec, bufOut, cfg, "", "", nil); err != nil {
+		return fmt.Errorf("error streaming genesis transformation: %v", err)
+	}
+	if err := bufOut.Flush(); err != nil {
+		return fmt.Errorf("error flushing output genesis file: %v", err)
+	}
+
+	fmt.Printf("Successfully streamed addresses from %s to %s and saved to %s\n", cfg.Account.From, cfg.Account.To, outputFile)
+	return nil
+}
+
+// streamTransformAny reads one JSON value from dec and writes the rewritten
+// value to w. path is the JSON pointer path to the value being read, and
+// module identifies the app_state key the walk descended from (empty outside
+// app_state), matching processAppState's bookkeeping so manifest entries line
+// up across both paths.
+func streamTransformAny(dec *json.Decoder, w *bufio.Writer, cfg config.Config, path, module string, mf *manifest.Manifest) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case json.Delim('{'):
+			return streamTransformObject(dec, w, cfg, path, module, mf)
+		case json.Delim('['):
+			return streamTransformArray(dec, w, cfg, path, module, mf)
+		default:
+			return fmt.Errorf("unexpected delimiter %q", t)
+		}
+	case string:
+		return writeJSONString(w, rewriteIfPrefixed(t, cfg, path, module, mf))
+	default:
+		return writeLiteral(w, t)
+	}
+}
+
+// streamTransformObject writes a '{' already consumed by the caller's Token
+// call, then streams each key/value pair, rewriting keys that carry a
+// configured prefix the same way string values are rewritten. A key read
+// directly under "/app_state" becomes the module for its own subtree, the
+// same rule processAppState uses when it splits app_state by top-level key.
+func streamTransformObject(dec *json.Decoder, w *bufio.Writer, cfg config.Config, path, module string, mf *manifest.Manifest) error {
+	if _, err := w.WriteString("{"); err != nil {
+		return err
+	}
+
+	first := true
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", keyTok)
+		}
+
+		if !first {
+			if _, err := w.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		childPath := manifest.JoinPath(path, key)
+		childModule := module
+		if path == "/app_state" {
+			childModule = key
+		}
+
+		if err := writeJSONString(w, rewriteIfPrefixed(key, cfg, childPath, childModule, mf)); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(":"); err != nil {
+			return err
+		}
+		if err := streamTransformAny(dec, w, cfg, childPath, childModule, mf); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil { // consume the closing '}'
+		return err
+	}
+	_, err := w.WriteString("}")
+	return err
+}
+
+// streamTransformArray streams each element of a JSON array.
+func streamTransformArray(dec *json.Decoder, w *bufio.Writer, cfg config.Config, path, module string, mf *manifest.Manifest) error {
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+
+	first := true
+	for i := 0; dec.More(); i++ {
+		if !first {
+			if _, err := w.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+		childPath := manifest.JoinIndex(path, i)
+		if err := streamTransformAny(dec, w, cfg, childPath, module, mf); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil { // consume the closing ']'
+		return err
+	}
+	_, err := w.WriteString("]")
+	return err
+}
+
+// rewriteIfPrefixed rewrites s if it carries one of cfg's configured
+// prefixes, and returns it unchanged otherwise.
+func rewriteIfPrefixed(s string, cfg config.Config, path, module string, mf *manifest.Manifest) string {
+	if !cfg.ContainsAnyPrefix(s) {
+		return s
+	}
+	return replaceAddressInString(s, cfg, path, module, mf)
+}
+
+// writeJSONString writes s as a properly escaped, quoted JSON string.
+func writeJSONString(w *bufio.Writer, s string) error {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
+
+// writeLiteral writes a non-string, non-delimiter JSON token (number, bool
+// or null) back out verbatim.
+func writeLiteral(w *bufio.Writer, tok json.Token) error {
+	switch t := tok.(type) {
+	case json.Number:
+		_, err := w.WriteString(t.String())
+		return err
+	case bool:
+		if t {
+			_, err := w.WriteString("true")
+			return err
+		}
+		_, err := w.WriteString("false")
+		return err
+	case nil:
+		_, err := w.WriteString("null")
+		return err
+	default:
+		return fmt.Errorf("unexpected token type %T", tok)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+)
+
+// verifyGenesisBytes parses data as a CometBFT genesis doc and runs
+// ValidateAndComplete over it. This catches breakage our slim GenesisDoc
+// wouldn't notice on its own - a chain_id that's gone missing or grown too
+// long, a malformed consensus_params, an initial_height that doesn't parse -
+// and it's the same validation genutiltypes.GenesisStateFromGenFile runs
+// before a node will start from the file.
+func verifyGenesisBytes(data []byte) error {
+	doc, err := cmttypes.GenesisDocFromJSON(data)
+	if err != nil {
+		return fmt.Errorf("parsing as a CometBFT genesis doc: %w", err)
+	}
+	if err := doc.ValidateAndComplete(); err != nil {
+		return fmt.Errorf("validating genesis doc: %w", err)
+	}
+	return nil
+}